@@ -0,0 +1,144 @@
+// Package cfg provides strongly-typed access to a u-blox receiver's
+// configuration database (UBX-CFG-VALSET / UBX-CFG-VALGET), on top of the
+// keys in configkeys.xml generated by cfggen.go.
+package cfg
+
+import (
+	"fmt"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// Layer selects which configuration layer(s) a VALSET write or VALGET read
+// applies to. They combine as a bitmask for writes.
+type Layer uint8
+
+const (
+	LayerRAM   Layer = 1 << 0
+	LayerBBR   Layer = 1 << 1
+	LayerFlash Layer = 1 << 2
+
+	// LayerDefault is valid only for VALGET: it reads the receiver's
+	// factory-default value for a key rather than a persisted one.
+	LayerDefault Layer = 1 << 3
+)
+
+// KeyID is a configuration item key as defined in the interface
+// description, e.g. 0x20140018 for CFG-NAVHPG-HPPOSMODE.
+type KeyID uint32
+
+// size returns the storage size in bytes implied by a key's upper byte,
+// per the u-blox key-ID layout (bits 28:30 encode the storage size).
+func (k KeyID) size() int {
+	switch (k >> 28) & 0x7 {
+	case 1:
+		return 1 // L (one bit, stored in one byte)
+	case 2:
+		return 1
+	case 3:
+		return 2
+	case 4:
+		return 4
+	case 5:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// KeyValue pairs a configuration key with a value to be written or that
+// was read back from the receiver.
+type KeyValue struct {
+	Key   KeyID
+	Value interface{}
+}
+
+// transaction bits, set in the first reserved byte of UBX-CFG-VALSET
+// version 1 on u-blox 9 / F9 receivers, so a configuration change spanning
+// multiple VALSET messages is applied atomically.
+const (
+	transBegin   = 1 << 0
+	transOngoing = 1 << 1
+	transCommit  = 1 << 2
+)
+
+// Transaction batches configuration key/value pairs and writes them to a
+// receiver as one or more UBX-CFG-VALSET messages, using the u-blox 9/F9
+// transaction bits so the whole batch is applied atomically.
+type Transaction struct {
+	session *ubx.Session
+	layers  Layer
+	kvs     []KeyValue
+}
+
+// NewTransaction starts a configuration transaction against session,
+// targeting the given layers (combine LayerRAM, LayerBBR, LayerFlash).
+func NewTransaction(session *ubx.Session, layers Layer) *Transaction {
+	return &Transaction{session: session, layers: layers}
+}
+
+// Set stages a key/value pair for the next Commit. It returns an error,
+// without staging anything, if key's storage size can't be determined —
+// that is, key is malformed or uses a reserved/unrecognized size class.
+func (t *Transaction) Set(key KeyID, value interface{}) error {
+	if key.size() == 0 {
+		return fmt.Errorf("cfg: key 0x%08x has an invalid or unrecognized size class", uint32(key))
+	}
+	t.kvs = append(t.kvs, KeyValue{Key: key, Value: value})
+	return nil
+}
+
+// maxValsetPayload caps the encoded key/value data (not counting the
+// 4-byte VALSET header) of a single UBX-CFG-VALSET message; Commit splits
+// larger transactions across multiple messages using the ongoing
+// transaction bit.
+const maxValsetPayload = 512
+
+// Commit sends the staged key/value pairs to the receiver, splitting them
+// across multiple UBX-CFG-VALSET messages if needed, with the begin bit
+// set on the first message, the commit bit on the last, and the ongoing
+// bit on every message so the receiver applies the whole batch atomically.
+func (t *Transaction) Commit() error {
+	if len(t.kvs) == 0 {
+		return nil
+	}
+
+	batches := t.batches()
+	for i, batch := range batches {
+		bits := uint8(transOngoing)
+		if i == 0 {
+			bits |= transBegin
+		}
+		if i == len(batches)-1 {
+			bits |= transCommit
+		}
+
+		msg := &valsetMessage{layers: t.layers, transaction: bits, kvs: batch}
+		if err := t.session.Send(msg); err != nil {
+			return fmt.Errorf("cfg: commit batch %d/%d: %w", i+1, len(batches), err)
+		}
+	}
+	return nil
+}
+
+// batches splits the staged key/value pairs so each UBX-CFG-VALSET message
+// stays within maxValsetPayload bytes of encoded key/value data.
+func (t *Transaction) batches() [][]KeyValue {
+	var batches [][]KeyValue
+	var cur []KeyValue
+	size := 0
+
+	for _, kv := range t.kvs {
+		kvSize := 4 + kv.Key.size()
+		if size+kvSize > maxValsetPayload && len(cur) > 0 {
+			batches = append(batches, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, kv)
+		size += kvSize
+	}
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+	return batches
+}
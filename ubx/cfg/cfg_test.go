@@ -0,0 +1,73 @@
+package cfg
+
+import "testing"
+
+func TestKeyIDSize(t *testing.T) {
+	tests := []struct {
+		key  KeyID
+		want int
+	}{
+		{0x10000000, 1}, // L (one bit, stored in one byte)
+		{0x20000000, 1}, // U1/I1/X1 (group 2)
+		{0x30000000, 2}, // U2/I2
+		{0x40000000, 4}, // U4/I4/X4/F4
+		{0x50000000, 8}, // U8/X8/R8
+		{0x00000000, 0}, // reserved
+		{0x70000000, 0}, // reserved
+	}
+	for _, tt := range tests {
+		if got := tt.key.size(); got != tt.want {
+			t.Errorf("KeyID(0x%08x).size() = %d, want %d", uint32(tt.key), got, tt.want)
+		}
+	}
+}
+
+func TestTransactionSetRejectsUnrecognizedSize(t *testing.T) {
+	tx := NewTransaction(nil, LayerRAM)
+	if err := tx.Set(KeyID(0x00000001), true); err == nil {
+		t.Error("Set with a reserved/unrecognized size class: want error, got nil")
+	}
+}
+
+func TestTransactionBatchesFitsOneBatch(t *testing.T) {
+	tx := NewTransaction(nil, LayerRAM)
+	for i := 0; i < 2; i++ {
+		if err := tx.Set(KeyID(0x40000000+uint32(i)), uint32(i)); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	batches := tx.batches()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("batches() = %v batches (sizes %v), want 1 batch of 2", len(batches), batchSizes(batches))
+	}
+}
+
+func TestTransactionBatchesSplitsAtPayloadLimit(t *testing.T) {
+	tx := NewTransaction(nil, LayerRAM)
+	// Each group-4 key/value pair costs 4 (key) + 4 (value) = 8 bytes, so
+	// maxValsetPayload (512) holds exactly 64 of them; the 65th must start
+	// a second batch.
+	const n = 65
+	for i := 0; i < n; i++ {
+		if err := tx.Set(KeyID(0x40000000+uint32(i)), uint32(i)); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	batches := tx.batches()
+	if len(batches) != 2 {
+		t.Fatalf("batches() = %v batches (sizes %v), want 2", len(batches), batchSizes(batches))
+	}
+	if len(batches[0]) != 64 || len(batches[1]) != 1 {
+		t.Errorf("batch sizes = %v, want [64 1]", batchSizes(batches))
+	}
+}
+
+func batchSizes(batches [][]KeyValue) []int {
+	sizes := make([]int, len(batches))
+	for i, b := range batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
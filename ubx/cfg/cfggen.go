@@ -0,0 +1,124 @@
+// This program generates keys_gen.go from configkeys.xml: one typed
+// accessor per configuration key, e.g. cfg.NavhpgHpposmode, plus an init()
+// that calls cfg.RegisterKeyName for every key so Dump can render names
+// instead of raw hex IDs.
+
+// +build ignore
+
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Catalog struct {
+	Key []*Key
+}
+
+type Key struct {
+	Name        string // e.g. CFG-NAVHPG-HPPOSMODE
+	Id          Hex    // e.g. 0x20140018
+	Type        string // the storage type: L, U1, I1, E1, U2, I2, U4, I4, X4, R4, R8
+	Group       string
+	Scale       string
+	Unit        string
+	Description string
+}
+
+type Hex uint64
+
+func (v *Hex) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var f string
+	if err := d.DecodeElement(&f, &start); err != nil {
+		return err
+	}
+	vv, err := strconv.ParseUint(f, 0, 64)
+	if err != nil {
+		return err
+	}
+	*v = Hex(vv)
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cfggen: ")
+	flag.Parse()
+
+	if len(flag.Args()) != 1 {
+		log.Fatalf("Usage: %s path/to/cfg.tmpl < configkeys.xml > keys_gen.go", os.Args[0])
+	}
+
+	tmpl, err := template.New(filepath.Base(flag.Arg(0))).Funcs(tmplfuncs).ParseFiles(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var catalog Catalog
+	if err := xml.NewDecoder(os.Stdin).Decode(&catalog); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("// Code generated by go run cfggen.go %s; DO NOT EDIT.\n", flag.Arg(0))
+
+	if err := tmpl.Execute(os.Stdout, catalog); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var tmplfuncs = template.FuncMap{
+	"goname": goName,
+	"gotype": goType,
+}
+
+// goName turns a key name like CFG-NAVHPG-HPPOSMODE into the exported Go
+// identifier NavhpgHpposmode its accessor is bound to: the leading "CFG"
+// part is dropped and each remaining part is title-cased as one word.
+func goName(s string) string {
+	parts := strings.Split(s, "-")
+	for i, v := range parts {
+		parts[i] = strings.Title(strings.ToLower(v))
+	}
+	return strings.Join(parts[1:], "")
+}
+
+// goType maps a configuration key's storage type to the Go type its typed
+// accessor's Get/Set use.
+func goType(ctype string) (string, error) {
+	switch ctype {
+	case "L":
+		return "bool", nil
+	case "U1":
+		return "uint8", nil
+	case "I1":
+		return "int8", nil
+	case "E1", "X1":
+		return "uint8", nil
+	case "U2":
+		return "uint16", nil
+	case "I2":
+		return "int16", nil
+	case "E2", "X2":
+		return "uint16", nil
+	case "U4":
+		return "uint32", nil
+	case "I4":
+		return "int32", nil
+	case "X4":
+		return "uint32", nil
+	case "R4":
+		return "float32", nil
+	case "R8":
+		return "float64", nil
+	default:
+		return "", fmt.Errorf("cfggen: unknown key storage type %q", ctype)
+	}
+}
@@ -0,0 +1,16 @@
+package cfg
+
+// Diff compares a receiver's current configuration against the desired
+// one and returns the key/value pairs that need to change: anything in
+// desired that's missing from current, or present with a different value.
+// The result is ready to hand to Transaction.Set.
+func Diff(current, desired map[KeyID]interface{}) []KeyValue {
+	var out []KeyValue
+	for key, want := range desired {
+		got, ok := current[key]
+		if !ok || got != want {
+			out = append(out, KeyValue{Key: key, Value: want})
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// Dump reads the given keys from layer and renders them as a flat
+// `name = value` listing — valid TOML, so it can be checked into version
+// control and diffed like any other config file. Pass no keys to fetch
+// every key the receiver reports. Key names come from RegisterKeyName,
+// which generated keys_gen.go populates from configkeys.xml; a key with
+// no registered name falls back to its raw `0xKEYID` form.
+func Dump(ctx context.Context, session *ubx.Session, layer Layer, keys ...KeyID) (string, error) {
+	kvs, err := Get(ctx, session, layer, keys...)
+	if err != nil {
+		return "", fmt.Errorf("cfg: dump: %w", err)
+	}
+
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+
+	var b strings.Builder
+	for _, kv := range kvs {
+		name, ok := KeyName(kv.Key)
+		if !ok {
+			name = fmt.Sprintf("0x%08x", uint32(kv.Key))
+		}
+		fmt.Fprintf(&b, "%s = %v\n", name, kv.Value)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,104 @@
+package cfg
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// openTestSession starts a Session against one end of an in-memory
+// net.Pipe, handing the caller the other end to play fake receiver on.
+func openTestSession(t *testing.T) (*ubx.Session, net.Conn) {
+	t.Helper()
+	client, device := net.Pipe()
+
+	opened := false
+	session, err := ubx.Open(context.Background(), func(context.Context) (io.ReadWriteCloser, error) {
+		if opened {
+			t.Fatal("opener called more than once")
+		}
+		opened = true
+		return client, nil
+	})
+	if err != nil {
+		t.Fatalf("ubx.Open: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+
+	return session, device
+}
+
+// fakeReceiver simulates a u-blox receiver that answers exactly one
+// UBX-CFG-VALGET poll with a single key/value pair, so Get/Dump can be
+// driven through a real Session end to end rather than only unit-tested
+// against hand-built Go values. It ignores the poll's contents beyond
+// draining its bytes off the wire.
+func fakeReceiver(t *testing.T, conn net.Conn, key KeyID, value byte) {
+	t.Helper()
+	rd := bufio.NewReader(conn)
+	var bad *ubx.BadFrameError
+	if _, err := ubx.ReadFrame(rd); err != nil && !errors.As(err, &bad) {
+		// decodeValgetReply isn't a meaningful decoder for a poll payload,
+		// so a BadFrameError here is expected; the poll's bytes are still
+		// fully consumed off the wire by the time ReadFrame returns. Any
+		// other error (e.g. a real I/O failure) is a real test failure.
+		t.Errorf("fakeReceiver: reading poll: %v", err)
+		return
+	}
+
+	var keyBytes [4]byte
+	binary.LittleEndian.PutUint32(keyBytes[:], uint32(key))
+	payload := append([]byte{valsetVersion, byte(LayerRAM), 0, 0}, keyBytes[:]...)
+	payload = append(payload, value)
+
+	if _, err := conn.Write(ubx.FrameUBX(classCFG, idCfgValget, payload)); err != nil {
+		t.Errorf("fakeReceiver: writing reply: %v", err)
+	}
+}
+
+func TestGetThroughSession(t *testing.T) {
+	session, device := openTestSession(t)
+
+	const key = KeyID(0x10000001)
+	const value = byte(42)
+	go fakeReceiver(t, device, key, value)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	kvs, err := Get(ctx, session, LayerRAM, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(kvs) != 1 || kvs[0].Key != key || kvs[0].Value != value {
+		t.Fatalf("Get = %+v, want [{%v %v}]", kvs, key, value)
+	}
+}
+
+func TestDumpThroughSession(t *testing.T) {
+	session, device := openTestSession(t)
+
+	const key = KeyID(0x10000002)
+	const value = byte(7)
+	RegisterKeyName(key, "CFG-TEST-DUMPKEY")
+	go fakeReceiver(t, device, key, value)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	out, err := Dump(ctx, session, LayerRAM, key)
+	if err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+	if !strings.Contains(out, "CFG-TEST-DUMPKEY = 7") {
+		t.Fatalf("Dump = %q, want it to contain %q", out, "CFG-TEST-DUMPKEY = 7")
+	}
+}
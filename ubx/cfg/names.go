@@ -0,0 +1,20 @@
+package cfg
+
+// keyNames maps a KeyID to its interface-description name, e.g.
+// "CFG-NAVHPG-HPPOSMODE" for 0x20140018. It's empty unless generated
+// keys_gen.go code has registered names via RegisterKeyName in its
+// init(); without that, key-name resolution falls back to the raw hex
+// key ID wherever it's needed (see Dump).
+var keyNames = map[KeyID]string{}
+
+// RegisterKeyName associates a KeyID with its catalog name. Called from
+// generated init() code, once per key in configkeys.xml.
+func RegisterKeyName(key KeyID, name string) {
+	keyNames[key] = name
+}
+
+// KeyName returns the catalog name registered for key, if any.
+func KeyName(key KeyID) (string, bool) {
+	name, ok := keyNames[key]
+	return name, ok
+}
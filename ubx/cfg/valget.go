@@ -0,0 +1,111 @@
+package cfg
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+const idCfgValget = 0x8b
+
+func init() {
+	ubx.RegisterDecoder(classCFG, idCfgValget, decodeValgetReply)
+}
+
+// valgetMessage is the poll sent to request one or more keys from a given
+// layer. An empty keys list asks the receiver for every key it has (only
+// meaningful combined with a small enough result to fit one reply).
+type valgetMessage struct {
+	layer Layer
+	keys  []KeyID
+}
+
+func (*valgetMessage) MsgClass() uint8 { return classCFG }
+func (*valgetMessage) MsgId() uint8    { return idCfgValget }
+
+func (m *valgetMessage) MarshalUBX() ([]byte, error) {
+	payload := []byte{valsetVersion, byte(m.layer), 0, 0}
+	for _, k := range m.keys {
+		var key [4]byte
+		binary.LittleEndian.PutUint32(key[:], uint32(k))
+		payload = append(payload, key[:]...)
+	}
+	return ubx.FrameUBX(classCFG, idCfgValget, payload), nil
+}
+
+// valgetReply is the decoded form of a UBX-CFG-VALGET response: the raw
+// key/value pairs the receiver returned, values still as little-endian
+// bytes since their Go type depends on the key.
+type valgetReply struct {
+	kvs []rawKeyValue
+}
+
+type rawKeyValue struct {
+	key   KeyID
+	bytes []byte
+}
+
+func (*valgetReply) MsgClass() uint8 { return classCFG }
+func (*valgetReply) MsgId() uint8    { return idCfgValget }
+
+func decodeValgetReply(payload []byte) (ubx.Message, error) {
+	if len(payload) < 4 {
+		return nil, fmt.Errorf("cfg: VALGET reply too short: %d bytes", len(payload))
+	}
+	reply := &valgetReply{}
+	for off := 4; off < len(payload); {
+		if off+4 > len(payload) {
+			return nil, fmt.Errorf("cfg: VALGET reply truncated at key offset %d", off)
+		}
+		key := KeyID(binary.LittleEndian.Uint32(payload[off:]))
+		off += 4
+
+		size := key.size()
+		if size == 0 || off+size > len(payload) {
+			return nil, fmt.Errorf("cfg: VALGET reply: bad size for key 0x%08x", key)
+		}
+		reply.kvs = append(reply.kvs, rawKeyValue{key: key, bytes: payload[off : off+size]})
+		off += size
+	}
+	return reply, nil
+}
+
+// Get fetches the given keys from layer and returns their current values.
+// Values are decoded to the narrowest unsigned integer type key.size()
+// implies; callers that need a signed or float interpretation should
+// convert accordingly, or use a generated typed accessor instead.
+func Get(ctx context.Context, session *ubx.Session, layer Layer, keys ...KeyID) ([]KeyValue, error) {
+	poll := &valgetMessage{layer: layer, keys: keys}
+
+	msg, err := session.Request(ctx, poll, classCFG, idCfgValget)
+	if err != nil {
+		return nil, fmt.Errorf("cfg: get: %w", err)
+	}
+	reply, ok := msg.(*valgetReply)
+	if !ok {
+		return nil, fmt.Errorf("cfg: get: unexpected reply type %T", msg)
+	}
+
+	out := make([]KeyValue, len(reply.kvs))
+	for i, kv := range reply.kvs {
+		out[i] = KeyValue{Key: kv.key, Value: decodeRawValue(kv.bytes)}
+	}
+	return out, nil
+}
+
+func decodeRawValue(b []byte) interface{} {
+	switch len(b) {
+	case 1:
+		return b[0]
+	case 2:
+		return binary.LittleEndian.Uint16(b)
+	case 4:
+		return binary.LittleEndian.Uint32(b)
+	case 8:
+		return binary.LittleEndian.Uint64(b)
+	default:
+		return b
+	}
+}
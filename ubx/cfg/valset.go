@@ -0,0 +1,91 @@
+package cfg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+const (
+	classCFG      = 0x06
+	idCfgValset   = 0x8a
+	valsetVersion = 1 // the version that carries the transaction byte
+)
+
+// valsetMessage is the Marshaler sent by Transaction.Commit. It is not
+// itself a generated type: VALSET's payload shape (a header plus a
+// variable run of key/value pairs) doesn't fit the generated Block model,
+// so it's built by hand the way the rest of this package builds it.
+type valsetMessage struct {
+	layers      Layer
+	transaction uint8
+	kvs         []KeyValue
+}
+
+func (*valsetMessage) MsgClass() uint8 { return classCFG }
+func (*valsetMessage) MsgId() uint8    { return idCfgValset }
+
+// MarshalUBX encodes the VALSET header and every staged key/value pair,
+// then wraps them in a UBX frame: sync bytes, class, id and length in
+// front, Fletcher-8 checksum after.
+func (m *valsetMessage) MarshalUBX() ([]byte, error) {
+	payload := []byte{valsetVersion, byte(m.layers), m.transaction, 0}
+
+	for _, kv := range m.kvs {
+		var key [4]byte
+		binary.LittleEndian.PutUint32(key[:], uint32(kv.Key))
+		payload = append(payload, key[:]...)
+
+		b, err := encodeValue(kv.Key, kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("cfg: key 0x%08x: %w", kv.Key, err)
+		}
+		payload = append(payload, b...)
+	}
+
+	return ubx.FrameUBX(classCFG, idCfgValset, payload), nil
+}
+
+// encodeValue packs value into the little-endian byte width key.size()
+// implies. value must be a fixed-width integer, bool or float32/float64
+// matching that width.
+func encodeValue(key KeyID, value interface{}) ([]byte, error) {
+	size := key.size()
+	if size == 0 {
+		return nil, fmt.Errorf("key 0x%08x has an invalid or unrecognized size class", uint32(key))
+	}
+	buf := make([]byte, size)
+
+	switch v := value.(type) {
+	case bool:
+		if v {
+			buf[0] = 1
+		}
+	case uint8:
+		buf[0] = v
+	case int8:
+		buf[0] = byte(v)
+	case uint16:
+		binary.LittleEndian.PutUint16(buf, v)
+	case int16:
+		binary.LittleEndian.PutUint16(buf, uint16(v))
+	case uint32:
+		binary.LittleEndian.PutUint32(buf, v)
+	case int32:
+		binary.LittleEndian.PutUint32(buf, uint32(v))
+	case float32:
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+	case uint64:
+		binary.LittleEndian.PutUint64(buf, v)
+	case int64:
+		binary.LittleEndian.PutUint64(buf, uint64(v))
+	case float64:
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,111 @@
+package ubx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Frame is implemented by every decoded message, regardless of which of
+// the three protocols a u-blox receiver speaks (UBX, NMEA 0183, RTCM3).
+// It lets a single reader demultiplex a mixed stream without the caller
+// needing to know which protocol produced a given message.
+//
+// Frame embeds the decoded Message as an interface field, so only
+// Message's own methods (MsgClass, MsgId) are promoted — a type
+// assertion on a Frame value itself cannot recover the concrete
+// generated type or any richer interface it implements. Call Unwrap to
+// get the underlying Message back out before asserting against it.
+type Frame interface {
+	Message
+	Protocol() string // "UBX", "NMEA", or "RTCM3"
+	Unwrap() Message  // the concrete decoded message, e.g. *NavPvt
+}
+
+// BadFrameError marks an error as belonging to a single malformed frame —
+// a checksum mismatch, a decoder that isn't registered — rather than to
+// the underlying connection. Callers like Session, which read a stream of
+// many frames, can use errors.As to tell "skip this frame and keep
+// reading" apart from "the connection itself is dead, reconnect".
+type BadFrameError struct{ Err error }
+
+func (e *BadFrameError) Error() string { return e.Err.Error() }
+func (e *BadFrameError) Unwrap() error { return e.Err }
+
+// ReadFrame reads and decodes exactly one frame from r, sniffing its sync
+// byte(s) to determine which of the three protocols it belongs to.
+func ReadFrame(r *bufio.Reader) (Frame, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch b {
+		case syncChar1:
+			b2, err := r.Peek(1)
+			if err != nil {
+				return nil, err
+			}
+			if b2[0] != syncChar2 {
+				continue
+			}
+			r.ReadByte()
+			return readUBXFrame(r)
+		case '$':
+			return readNMEASentence(r)
+		case rtcmPreamble:
+			return readRTCMFrame(r)
+		default:
+			continue // not a recognised sync byte; keep scanning
+		}
+	}
+}
+
+// readUBXFrame reads the remainder of a UBX frame (class, id, length,
+// payload, checksum) assuming the two sync bytes have already been
+// consumed, and decodes it via the generated decoder registry.
+func readUBXFrame(r *bufio.Reader) (Frame, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	class, id := header[0], header[1]
+	length := uint16(header[2]) | uint16(header[3])<<8
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	ck := make([]byte, 2)
+	if _, err := io.ReadFull(r, ck); err != nil {
+		return nil, err
+	}
+	ck1, ck2 := fletcher8(append(append([]byte{}, header...), payload...))
+	if ck1 != ck[0] || ck2 != ck[1] {
+		return nil, &BadFrameError{fmt.Errorf("ubx: checksum mismatch for class 0x%02x id 0x%02x", class, id)}
+	}
+
+	msg, err := decode(class, id, payload)
+	if err != nil {
+		return nil, &BadFrameError{err}
+	}
+	return ubxFrame{msg}, nil
+}
+
+// ubxFrame adapts a decoded UBX Message to Frame.
+type ubxFrame struct {
+	Message
+}
+
+func (ubxFrame) Protocol() string { return "UBX" }
+
+// Unwrap returns the decoded Message this frame wraps.
+func (f ubxFrame) Unwrap() Message { return f.Message }
+
+// MarshalJSON marshals the wrapped Message directly. Without this, Go's
+// encoding/json treats the anonymous Message field as named "Message"
+// rather than promoting it, burying every real field one level down.
+func (f ubxFrame) MarshalJSON() ([]byte, error) { return json.Marshal(f.Message) }
@@ -1,4 +1,22 @@
-// This program generates messages.go from messages.xml
+// This program generates messages.go from messages.xml. It also reads the
+// <NMEA> and <RTCM> elements of the same document, if present, to generate
+// NMEA 0183 and RTCM3 decoders that share the Frame interface with UBX
+// messages so callers can demultiplex a mixed stream from a single reader.
+//
+// code.tmpl uses the shift/fieldsize/lengthterms functions below, on top
+// of gotype and mask, to emit a MarshalUBX() ([]byte, error) method next
+// to every generated decoder: fieldsize says how many bytes to advance
+// per field (array-aware), lengthterms gives the fixed and per-repeat
+// sizes a Length formula like "8 + N * 12" implies for repeated Blocks,
+// and shift complements mask() to pack a Bitfield entry's bits into its
+// host field instead of reading them out of it.
+//
+// For any Block with a non-empty Scale, code.tmpl also emits a second,
+// physical-unit accessor alongside the raw integer field: unittype maps
+// the Block's Unit to a type in the units subpackage (falling back to
+// plain float64 when Unit is empty or unrecognized), and scale gives the
+// Go literal to multiply (decode) or divide (encode, with rounding and an
+// overflow check against the raw field's integer range) by.
 
 // +build ignore
 
@@ -10,6 +28,7 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,12 +38,71 @@ import (
 
 type Definitions struct {
 	Message []*Message
+	NMEA    []*NMEASentence `xml:"NMEA"`
+	RTCM    []*RTCMMessage  `xml:"RTCM"`
 }
 
 func (d *Definitions) Link() {
 	for _, v := range d.Message {
 		v.Link()
 	}
+	for _, v := range d.NMEA {
+		v.Link()
+	}
+	for _, v := range d.RTCM {
+		v.Link()
+	}
+}
+
+// NMEASentence describes one NMEA 0183 sentence, e.g. GGA or RMC. Talker ID
+// is not part of the definition: a single decoder handles any talker
+// ("GP", "GN", "GL", ...) since the field layout is talker-independent.
+type NMEASentence struct {
+	Name        string
+	Description string
+	Comment     string
+	Fields      []*NMEAField `xml:"Payload>Field"`
+}
+
+func (n *NMEASentence) Link() {
+	for _, v := range n.Fields {
+		v.Sentence = n
+	}
+}
+
+// NMEAField is one comma-separated field of an NMEA sentence.
+type NMEAField struct {
+	Name    string
+	Type    string // e.g. LAT, LON, UTCTIME, TALKER, STATUS, CH, U1, R4 ...
+	Comment string
+
+	Sentence *NMEASentence `xml:"-"`
+}
+
+// RTCMMessage describes one RTCM3 message type, identified by its message
+// number (e.g. 1005, 1074), as a sequence of bit-packed DF fields.
+type RTCMMessage struct {
+	Number      string
+	Description string
+	Comment     string
+	Fields      []*RTCMField `xml:"Payload>Field"`
+}
+
+func (r *RTCMMessage) Link() {
+	for _, v := range r.Fields {
+		v.Message = r
+	}
+}
+
+// RTCMField is one DF-numbered bitfield of an RTCM3 message.
+type RTCMField struct {
+	DF      string
+	Name    string
+	Type    string // e.g. UBITN, SBITN, BITN with N substituted by the bit width
+	Scale   string
+	Comment string
+
+	Message *RTCMMessage `xml:"-"`
 }
 
 type Message struct {
@@ -135,6 +213,13 @@ var tmplfuncs = template.FuncMap{
 	"msgtypename": msgtypename,
 	"gotype":      goType,
 	"mask":        mask,
+	"nmeatype":    nmeaGoType,
+	"rtcmtype":    rtcmGoType,
+	"shift":       shift,
+	"fieldsize":   fieldsize,
+	"lengthterms": lengthTerms,
+	"unittype":    unitType,
+	"scale":       scaleLiteral,
 }
 
 var wstospace = strings.NewReplacer("\t", " ", "\n", " ")
@@ -189,6 +274,64 @@ func goType(ctype string) (string, error) {
 	return fmt.Sprintf("%s%s", parts[2], t), nil
 }
 
+var reNMEAType = regexp.MustCompile(`^(TALKER|STATUS|CH|LAT|LON|UTCTIME|UTCDATE|[CHIRUX0-9_]+)(\[[0-9]+\])?$`)
+
+// nmeaGoType maps an NMEA field type to the Go type used to hold its
+// decoded value. Positional fields that need receiver-specific parsing
+// (coordinates in ddmm.mmmm form, UTC time/date, the talker ID itself) get
+// dedicated Go types so the decoder, not the caller, owns the parsing.
+func nmeaGoType(ctype string) (string, error) {
+	parts := reNMEAType.FindStringSubmatch(ctype)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("Cannot parse %q as an NMEA field type", ctype)
+	}
+	switch parts[1] {
+	case "TALKER":
+		return "TalkerID", nil
+	case "LAT", "LON":
+		return "float64", nil // decoded from ddmm.mmmm into signed decimal degrees
+	case "UTCTIME":
+		return "time.Duration", nil // offset since midnight UTC
+	case "UTCDATE":
+		return "time.Time", nil
+	case "STATUS":
+		return "bool", nil // 'A' (valid) or 'V' (void)
+	default:
+		return goType(parts[1] + parts[2])
+	}
+}
+
+var reRTCMType = regexp.MustCompile(`^([US]?BIT)([0-9]+)$`)
+
+// rtcmGoType maps an RTCM3 DF bitfield type (UBITn/SBITn/BITn, n the bit
+// width) to the narrowest Go integer type that can hold it.
+func rtcmGoType(ctype string) (string, error) {
+	parts := reRTCMType.FindStringSubmatch(ctype)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("Cannot parse %q as an RTCM3 bitfield type (want [U]BITn)", ctype)
+	}
+	signed := parts[1] == "SBIT"
+	n, err := strconv.Atoi(parts[2])
+	if err != nil || n < 1 || n > 64 {
+		return "", fmt.Errorf("Cannot parse %q as an RTCM3 bitfield type (invalid width)", ctype)
+	}
+	var size int
+	switch {
+	case n <= 8:
+		size = 8
+	case n <= 16:
+		size = 16
+	case n <= 32:
+		size = 32
+	default:
+		size = 64
+	}
+	if signed {
+		return fmt.Sprintf("int%d", size), nil
+	}
+	return fmt.Sprintf("uint%d", size), nil
+}
+
 func mask(s string) string {
 	parts := strings.Split(s, ":")
 	if len(parts) == 2 {
@@ -199,3 +342,116 @@ func mask(s string) string {
 	i, _ := strconv.ParseUint(s, 0, 8)
 	return fmt.Sprintf("0x%x", 1<<i)
 }
+
+// shift returns the low bit position of a Bitfield Index ("hi:lo" or a
+// single bit), the complement to mask(): decoding reads (raw & mask) and
+// marshaling packs (value << shift) | existing.
+func shift(s string) string {
+	parts := strings.Split(s, ":")
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return s
+}
+
+// fieldsize returns the number of bytes a scalar ctype occupies on the
+// wire, the size MarshalUBX needs to know how far to advance the buffer
+// for each field (arrays occupy len*elemsize bytes).
+func fieldsize(ctype string) (int, error) {
+	parts := reCType.FindStringSubmatch(ctype)
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("Cannot parse %q as ctype([arraylen])", ctype)
+	}
+	var n int
+	switch parts[1] {
+	case "I1", "U1", "CH", "X1":
+		n = 1
+	case "I2", "U2", "X2":
+		n = 2
+	case "I4", "U4", "X4", "R4":
+		n = 4
+	case "I8", "U8", "R8":
+		n = 8
+	case "RU1_3":
+		n = 1
+	default:
+		return 0, fmt.Errorf("Cannot parse %q as a ctype (invalid scalar part %q)", ctype, parts[1])
+	}
+	if parts[2] == "" {
+		return n, nil
+	}
+	length, err := strconv.Atoi(strings.Trim(parts[2], "[]"))
+	if err != nil {
+		return 0, fmt.Errorf("Cannot parse array length in %q: %w", ctype, err)
+	}
+	return n * length, nil
+}
+
+// lengthTerms splits a Message's Length field ("A + N * B" in varying
+// syntax) into its fixed part A and, for messages with a repeated block,
+// the per-repeat size B. Messages without a repeated Block have no N*B
+// term and b is returned as 0.
+func lengthTerms(length string) (a, b int, err error) {
+	length = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, length)
+
+	parts := strings.SplitN(length, "+", 2)
+	a, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Cannot parse %q as a length formula (fixed part): %w", length, err)
+	}
+	if len(parts) == 1 {
+		return a, 0, nil
+	}
+
+	mul := strings.SplitN(parts[1], "*", 2)
+	if len(mul) != 2 {
+		return 0, 0, fmt.Errorf("Cannot parse %q as a length formula (N*B part)", length)
+	}
+	b, err = strconv.Atoi(mul[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Cannot parse %q as a length formula (per-repeat size): %w", length, err)
+	}
+	return a, b, nil
+}
+
+// unitType maps a Block's Unit string, as written in messages.xml (e.g.
+// "deg", "m", "m/s", "s"), to the units subpackage type its scale-aware
+// accessor returns. Blocks with a Scale but no recognized Unit fall back
+// to plain float64, still scaled, just without a named physical type.
+func unitType(unit string) (string, error) {
+	switch unit {
+	case "deg":
+		return "units.Degrees", nil
+	case "m":
+		return "units.Meters", nil
+	case "m/s":
+		return "units.MetersPerSecond", nil
+	case "s":
+		return "units.Seconds", nil
+	case "":
+		return "float64", nil
+	default:
+		return "float64", nil
+	}
+}
+
+// scaleLiteral turns a Block's Scale string (e.g. "1e-7") into the Go
+// floating-point literal the generated accessor multiplies by to convert
+// the raw integer field to its physical value, and divides by to invert
+// a setter's physical value back to the raw field, with rounding and
+// overflow checked against the field's integer type before the cast.
+func scaleLiteral(scale string) (string, error) {
+	if scale == "" {
+		return "", fmt.Errorf("scaleLiteral called on a Block with no Scale")
+	}
+	f, err := strconv.ParseFloat(scale, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("Cannot parse %q as a scale factor", scale)
+	}
+	return scale, nil
+}
@@ -0,0 +1,116 @@
+package ubx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TalkerID is the two-letter NMEA talker prefix, e.g. "GP" (GPS), "GN"
+// (multi-constellation), "GL" (GLONASS).
+type TalkerID string
+
+// NMEADecoder turns the comma-separated fields of a sentence (with the
+// talker ID and checksum already stripped) into a typed Message.
+type NMEADecoder func(talker TalkerID, fields []string) (Message, error)
+
+var nmeaDecoders = map[string]NMEADecoder{}
+
+// RegisterNMEADecoder associates a sentence type, e.g. "GGA", with the
+// function that decodes its fields. Called from generated init() code.
+func RegisterNMEADecoder(sentenceType string, fn NMEADecoder) {
+	nmeaDecoders[sentenceType] = fn
+}
+
+// nmeaChecksum computes the XOR checksum NMEA 0183 uses, over every byte
+// between (exclusive) the leading '$' and the trailing '*'.
+func nmeaChecksum(s string) byte {
+	var ck byte
+	for i := 0; i < len(s); i++ {
+		ck ^= s[i]
+	}
+	return ck
+}
+
+type nmeaFrame struct {
+	Message
+}
+
+func (nmeaFrame) Protocol() string { return "NMEA" }
+
+// Unwrap returns the decoded Message this frame wraps.
+func (f nmeaFrame) Unwrap() Message { return f.Message }
+
+// MarshalJSON marshals the wrapped Message directly; see ubxFrame.MarshalJSON.
+func (f nmeaFrame) MarshalJSON() ([]byte, error) { return json.Marshal(f.Message) }
+
+// readNMEASentence reads the remainder of an NMEA sentence assuming the
+// leading '$' has already been consumed, validates its checksum, and
+// dispatches to the decoder registered for its sentence type.
+func readNMEASentence(r *bufio.Reader) (Frame, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	star := strings.LastIndexByte(line, '*')
+	if star < 0 || star+3 > len(line) {
+		return nil, &BadFrameError{fmt.Errorf("nmea: missing checksum in %q", line)}
+	}
+	body, wantHex := line[:star], line[star+1:star+3]
+	want, err := strconv.ParseUint(wantHex, 16, 8)
+	if err != nil {
+		return nil, &BadFrameError{fmt.Errorf("nmea: bad checksum %q: %w", wantHex, err)}
+	}
+	if got := nmeaChecksum(body); got != byte(want) {
+		return nil, &BadFrameError{fmt.Errorf("nmea: checksum mismatch for %q: got 0x%02x want 0x%02x", body, got, want)}
+	}
+
+	fields := strings.Split(body, ",")
+	if len(fields) == 0 || len(fields[0]) != 5 {
+		return nil, &BadFrameError{fmt.Errorf("nmea: malformed sentence id %q", body)}
+	}
+	talker, sentenceType := TalkerID(fields[0][:2]), fields[0][2:]
+
+	fn, ok := nmeaDecoders[sentenceType]
+	if !ok {
+		return nil, &BadFrameError{fmt.Errorf("nmea: no decoder registered for sentence type %q", sentenceType)}
+	}
+	msg, err := fn(talker, fields[1:])
+	if err != nil {
+		return nil, &BadFrameError{err}
+	}
+	return nmeaFrame{msg}, nil
+}
+
+// parseLatLon converts an NMEA coordinate in ddmm.mmmm (or dddmm.mmmm for
+// longitude) form plus its hemisphere letter into signed decimal degrees.
+func parseLatLon(ddmm, hemisphere string) (float64, error) {
+	dot := strings.IndexByte(ddmm, '.')
+	if dot < 2 {
+		return 0, fmt.Errorf("nmea: malformed coordinate %q", ddmm)
+	}
+	degDigits := dot - 2
+
+	deg, err := strconv.ParseFloat(ddmm[:degDigits], 64)
+	if err != nil {
+		return 0, fmt.Errorf("nmea: malformed coordinate %q: %w", ddmm, err)
+	}
+	min, err := strconv.ParseFloat(ddmm[degDigits:], 64)
+	if err != nil {
+		return 0, fmt.Errorf("nmea: malformed coordinate %q: %w", ddmm, err)
+	}
+
+	v := deg + min/60
+	switch hemisphere {
+	case "S", "W":
+		v = -v
+	case "N", "E":
+	default:
+		return 0, fmt.Errorf("nmea: unknown hemisphere %q", hemisphere)
+	}
+	return v, nil
+}
@@ -0,0 +1,36 @@
+package ubx
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseLatLon(t *testing.T) {
+	tests := []struct {
+		ddmm, hemisphere string
+		want             float64
+	}{
+		{"4916.45", "N", 49 + 16.45/60},
+		{"12311.12", "W", -(123 + 11.12/60)},
+		{"0000.00", "E", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseLatLon(tt.ddmm, tt.hemisphere)
+		if err != nil {
+			t.Errorf("parseLatLon(%q, %q): unexpected error: %v", tt.ddmm, tt.hemisphere, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("parseLatLon(%q, %q) = %v, want %v", tt.ddmm, tt.hemisphere, got, tt.want)
+		}
+	}
+}
+
+func TestParseLatLonErrors(t *testing.T) {
+	if _, err := parseLatLon("4916.45", "X"); err == nil {
+		t.Error("parseLatLon with unknown hemisphere: want error, got nil")
+	}
+	if _, err := parseLatLon("not-a-coordinate", "N"); err == nil {
+		t.Error("parseLatLon with malformed coordinate: want error, got nil")
+	}
+}
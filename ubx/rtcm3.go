@@ -0,0 +1,119 @@
+package ubx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const rtcmPreamble = 0xD3
+
+// RTCMDecoder turns an RTCM3 message's bit-packed payload into a typed
+// Message. payload still begins with DF002 (the 12-bit message number);
+// bitOffset is 12, the first bit after it.
+type RTCMDecoder func(payload []byte, bitOffset int) (Message, error)
+
+var rtcmDecoders = map[int]RTCMDecoder{}
+
+// RegisterRTCMDecoder associates an RTCM3 message number, e.g. 1005, with
+// the function that decodes its payload. Called from generated init() code.
+func RegisterRTCMDecoder(number int, fn RTCMDecoder) {
+	rtcmDecoders[number] = fn
+}
+
+type rtcmFrame struct {
+	Message
+}
+
+func (rtcmFrame) Protocol() string { return "RTCM3" }
+
+// Unwrap returns the decoded Message this frame wraps.
+func (f rtcmFrame) Unwrap() Message { return f.Message }
+
+// MarshalJSON marshals the wrapped Message directly; see ubxFrame.MarshalJSON.
+func (f rtcmFrame) MarshalJSON() ([]byte, error) { return json.Marshal(f.Message) }
+
+// readRTCMFrame reads the remainder of an RTCM3 frame assuming the
+// preamble byte has already been consumed: a 10-bit length (in a 2-byte,
+// 6-reserved-bit header), the payload, and a 24-bit CRC.
+func readRTCMFrame(r *bufio.Reader) (Frame, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := (int(header[0]&0x03) << 8) | int(header[1])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	crc := make([]byte, 3)
+	if _, err := io.ReadFull(r, crc); err != nil {
+		return nil, err
+	}
+
+	full := make([]byte, 0, 3+length)
+	full = append(full, rtcmPreamble)
+	full = append(full, header...)
+	full = append(full, payload...)
+	want := uint32(crc[0])<<16 | uint32(crc[1])<<8 | uint32(crc[2])
+	if got := crc24q(full); got != want {
+		return nil, &BadFrameError{fmt.Errorf("rtcm3: CRC mismatch: got 0x%06x want 0x%06x", got, want)}
+	}
+
+	number := bitsUint(payload, 0, 12)
+
+	fn, ok := rtcmDecoders[int(number)]
+	if !ok {
+		return nil, &BadFrameError{fmt.Errorf("rtcm3: no decoder registered for message %d", number)}
+	}
+	msg, err := fn(payload, 12)
+	if err != nil {
+		return nil, &BadFrameError{err}
+	}
+	return rtcmFrame{msg}, nil
+}
+
+// crc24q computes the 24-bit Qualcomm CRC (polynomial 0x1864CFB) RTCM3
+// uses to protect the preamble, header and payload of every frame.
+func crc24q(data []byte) uint32 {
+	const poly = 0x1864CFB
+	var crc uint32
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= poly
+			}
+		}
+	}
+	return crc & 0xFFFFFF
+}
+
+// bitsUint reads an unsigned, big-endian bitfield of width bits starting
+// at bitOffset (0 is the MSB of data[0]) — the packing RTCM3 DF fields use.
+func bitsUint(data []byte, bitOffset, width int) uint64 {
+	var v uint64
+	for i := 0; i < width; i++ {
+		byteIdx := (bitOffset + i) / 8
+		bitIdx := 7 - (bitOffset+i)%8
+		bit := (data[byteIdx] >> bitIdx) & 1
+		v = v<<1 | uint64(bit)
+	}
+	return v
+}
+
+// bitsInt reads a signed, sign-magnitude bitfield: RTCM3 stores the sign
+// as the top bit rather than using two's complement, unlike UBX's I-types.
+func bitsInt(data []byte, bitOffset, width int) int64 {
+	raw := bitsUint(data, bitOffset, width)
+	sign := raw>>(width-1) != 0
+	magnitude := int64(raw &^ (1 << uint(width-1)))
+	if sign {
+		return -magnitude
+	}
+	return magnitude
+}
@@ -0,0 +1,45 @@
+package ubx
+
+import "testing"
+
+func TestCRC24Q(t *testing.T) {
+	// CRC24Q is a pure polynomial division with a zero initial value and no
+	// final XOR, so appending a message's own CRC to itself and recomputing
+	// must always yield zero.
+	data := []byte("123456789")
+	crc := crc24q(data)
+
+	full := append(append([]byte{}, data...), byte(crc>>16), byte(crc>>8), byte(crc))
+	if got := crc24q(full); got != 0 {
+		t.Errorf("crc24q(data+crc24q(data)) = 0x%06x, want 0", got)
+	}
+}
+
+func TestBitsUint(t *testing.T) {
+	// 1011 0100 1100 .... (top 12 bits read MSB-first) = 0xB4C.
+	data := []byte{0xB4, 0xC0}
+	if got, want := bitsUint(data, 0, 12), uint64(0xB4C); got != want {
+		t.Errorf("bitsUint(% x, 0, 12) = 0x%x, want 0x%x", data, got, want)
+	}
+
+	// Reading from a non-zero bit offset: bits [4:12) of the same data are
+	// 0100 1100 = 0x4C.
+	if got, want := bitsUint(data, 4, 8), uint64(0x4C); got != want {
+		t.Errorf("bitsUint(% x, 4, 8) = 0x%x, want 0x%x", data, got, want)
+	}
+}
+
+func TestBitsInt(t *testing.T) {
+	// Same 12-bit field as TestBitsUint (0xB4C): top bit set, so RTCM3's
+	// sign-magnitude encoding reads this as -(0xB4C &^ 0x800) == -844.
+	data := []byte{0xB4, 0xC0}
+	if got, want := bitsInt(data, 0, 12), int64(-844); got != want {
+		t.Errorf("bitsInt(% x, 0, 12) = %d, want %d", data, got, want)
+	}
+
+	// Sign bit clear: reads as a plain positive magnitude.
+	data = []byte{0x34, 0xC0}
+	if got, want := bitsInt(data, 0, 12), int64(0x34C); got != want {
+		t.Errorf("bitsInt(% x, 0, 12) = %d, want %d", data, got, want)
+	}
+}
@@ -0,0 +1,392 @@
+package ubx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Message is implemented by every type generated from messages.xml.
+type Message interface {
+	MsgClass() uint8
+	MsgId() uint8
+}
+
+// Marshaler is implemented by generated message types that can be sent to
+// a receiver (polls, CFG writes, and the like).
+type Marshaler interface {
+	Message
+	MarshalUBX() ([]byte, error)
+}
+
+// Decoder turns a UBX payload into a typed Message. Generated code registers
+// one of these per (class, id) pair via RegisterDecoder.
+type Decoder func(payload []byte) (Message, error)
+
+type msgKey struct {
+	class, id uint8
+}
+
+var decoders = map[msgKey]Decoder{}
+
+// RegisterDecoder associates a (class, id) pair with the function that
+// decodes its payload. Called from generated init() code.
+func RegisterDecoder(class, id uint8, fn Decoder) {
+	decoders[msgKey{class, id}] = fn
+}
+
+func decode(class, id uint8, payload []byte) (Message, error) {
+	fn, ok := decoders[msgKey{class, id}]
+	if !ok {
+		return nil, fmt.Errorf("ubx: no decoder registered for class 0x%02x id 0x%02x", class, id)
+	}
+	return fn(payload)
+}
+
+const (
+	syncChar1 = 0xB5
+	syncChar2 = 0x62
+)
+
+// fletcher8 computes the two-byte UBX checksum over class, id, length and
+// payload, per the Fletcher-8 algorithm in the interface description.
+func fletcher8(data []byte) (ck1, ck2 byte) {
+	for _, b := range data {
+		ck1 += b
+		ck2 += ck1
+	}
+	return ck1, ck2
+}
+
+// ClassMetrics holds running counters for messages seen on a given class.
+type ClassMetrics struct {
+	Messages uint64
+	Bytes    uint64
+}
+
+// Opener creates a fresh connection to the receiver, e.g. opening a serial
+// port or USB CDC device. Session calls it on first connect and again after
+// an I/O error, with backoff between attempts.
+type Opener func(ctx context.Context) (io.ReadWriteCloser, error)
+
+// Session manages a live connection to a u-blox receiver: framing, checksum
+// validation, resync on desync, reconnect with backoff, and dispatch of
+// decoded messages to subscribers.
+type Session struct {
+	open Opener
+
+	maxBackoff time.Duration
+
+	mu   sync.Mutex
+	conn io.ReadWriteCloser
+
+	subMu sync.Mutex
+	subs  map[msgKey][]chan Message
+
+	metricsMu sync.Mutex
+	metrics   map[uint8]*ClassMetrics
+
+	done   chan struct{}
+	closed chan struct{}
+}
+
+// Option configures a Session.
+type Option func(*Session)
+
+// WithMaxBackoff caps the delay between reconnect attempts. The default is
+// 30 seconds.
+func WithMaxBackoff(d time.Duration) Option {
+	return func(s *Session) { s.maxBackoff = d }
+}
+
+// Open starts a Session against the connection produced by open, and begins
+// the read loop in the background. The returned Session reconnects
+// automatically, using open again, if the connection is lost.
+func Open(ctx context.Context, open Opener, opts ...Option) (*Session, error) {
+	s := &Session{
+		open:       open,
+		maxBackoff: 30 * time.Second,
+		subs:       make(map[msgKey][]chan Message),
+		metrics:    make(map[uint8]*ClassMetrics),
+		done:       make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	conn, err := open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ubx: open: %w", err)
+	}
+	s.conn = conn
+
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// Close stops the read loop, closes every channel returned by Subscribe
+// that has not been removed by Unsubscribe, and closes the underlying
+// connection.
+func (s *Session) Close() error {
+	close(s.done)
+
+	// run() is normally blocked in conn.Read, waiting on the next byte from
+	// an idle receiver, not in the select that notices s.done. Close the
+	// connection now so that Read unblocks with an error and run() can
+	// actually get to s.done and return, instead of waiting forever.
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	var closeErr error
+	if conn != nil {
+		closeErr = conn.Close()
+	}
+
+	<-s.closed // run() has exited, so dispatch won't touch s.subs again
+
+	s.subMu.Lock()
+	for _, chans := range s.subs {
+		for _, ch := range chans {
+			close(ch)
+		}
+	}
+	s.subs = nil
+	s.subMu.Unlock()
+
+	return closeErr
+}
+
+// Metrics returns a snapshot of the per-class message counters.
+func (s *Session) Metrics() map[uint8]ClassMetrics {
+	s.metricsMu.Lock()
+	defer s.metricsMu.Unlock()
+	out := make(map[uint8]ClassMetrics, len(s.metrics))
+	for class, m := range s.metrics {
+		out[class] = *m
+	}
+	return out
+}
+
+// Subscribe returns a channel of decoded messages matching class and id.
+// The channel is closed when the Session is closed, unless Unsubscribe has
+// removed it first (Unsubscribe does not close it). Subscribers must keep
+// up with the stream; a slow
+// consumer drops messages rather than blocking the read loop. Callers
+// that subscribe for the lifetime of a single request, such as Request,
+// must Unsubscribe when done or the channel leaks for the Session's life.
+// Subscribing after the Session is closed returns an already-closed
+// channel rather than panicking or blocking forever.
+func (s *Session) Subscribe(class, id uint8) <-chan Message {
+	ch := make(chan Message, 32)
+	key := msgKey{class, id}
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	if s.subs == nil { // Close has already run
+		close(ch)
+		return ch
+	}
+	s.subs[key] = append(s.subs[key], ch)
+	return ch
+}
+
+// Unsubscribe removes ch, previously returned by Subscribe for the same
+// class and id, so it stops receiving messages and the Session no longer
+// keeps it reachable. It deliberately does not close ch: dispatch may be
+// mid-send to it concurrently, and closing out from under that send would
+// panic. Calling it with a channel that's already been removed (including
+// by Close) is a no-op.
+func (s *Session) Unsubscribe(class, id uint8, ch <-chan Message) {
+	key := msgKey{class, id}
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	chans := s.subs[key]
+	for i, c := range chans {
+		if c == ch {
+			s.subs[key] = append(chans[:i], chans[i+1:]...)
+			return
+		}
+	}
+}
+
+// Send writes msg to the receiver without waiting for a reply.
+func (s *Session) Send(msg Marshaler) error {
+	frame, err := msg.MarshalUBX()
+	if err != nil {
+		return fmt.Errorf("ubx: marshal %T: %w", msg, err)
+	}
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("ubx: not connected")
+	}
+
+	_, err = conn.Write(frame)
+	return err
+}
+
+// Poll sends msg as a poll request: the receiver is expected to reply with
+// the same class/id carrying the current value, rather than an ack.
+func (s *Session) Poll(msg Marshaler) error {
+	return s.Send(msg)
+}
+
+// Request sends poll and waits for the first message matching replyClass
+// and replyId, or until ctx is done.
+func (s *Session) Request(ctx context.Context, poll Marshaler, replyClass, replyId uint8) (Message, error) {
+	ch := s.Subscribe(replyClass, replyId)
+	defer s.Unsubscribe(replyClass, replyId, ch)
+
+	if err := s.Send(poll); err != nil {
+		return nil, err
+	}
+
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			return nil, errors.New("ubx: session closed while waiting for reply")
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Session) dispatch(msg Message) {
+	key := msgKey{msg.MsgClass(), msg.MsgId()}
+
+	s.metricsMu.Lock()
+	m, ok := s.metrics[msg.MsgClass()]
+	if !ok {
+		m = &ClassMetrics{}
+		s.metrics[msg.MsgClass()] = m
+	}
+	m.Messages++
+	s.metricsMu.Unlock()
+
+	s.subMu.Lock()
+	chans := s.subs[key]
+	s.subMu.Unlock()
+
+	for _, ch := range chans {
+		select {
+		case ch <- msg:
+		default:
+			// slow consumer: drop rather than block the read loop
+		}
+	}
+}
+
+// run owns the connect/read/reconnect lifecycle for the Session's lifetime.
+func (s *Session) run(ctx context.Context) {
+	defer close(s.closed)
+
+	backoff := time.Second
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+
+		err := s.readFrames(conn)
+		if err == nil {
+			return // s.done closed the connection out from under us
+		}
+
+		conn.Close()
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+
+		newConn, err := s.open(ctx)
+		if err != nil {
+			continue // try again after another backoff
+		}
+		backoff = time.Second
+
+		s.mu.Lock()
+		s.conn = newConn
+		s.mu.Unlock()
+	}
+}
+
+// readFrames reads and dispatches frames from conn until an I/O error or
+// Close is observed. It returns nil only when the Session is being shut
+// down. Framing, sync and checksum validation are shared with the
+// ubx.ReadFrame demuxer (frame.go) rather than reimplemented here; a bad
+// frame (checksum mismatch, no registered decoder) is skipped via
+// BadFrameError rather than treated as a connection failure.
+func (s *Session) readFrames(conn io.ReadWriteCloser) error {
+	var total int64
+	cr := &countingReader{r: conn, n: &total}
+	r := bufio.NewReader(cr)
+
+	for {
+		select {
+		case <-s.done:
+			return nil
+		default:
+		}
+
+		before := total - int64(r.Buffered())
+		frame, err := ReadFrame(r)
+		if err != nil {
+			var bad *BadFrameError
+			if errors.As(err, &bad) {
+				continue // this frame is garbage; the stream itself is fine
+			}
+			return err
+		}
+		consumed := (total - int64(r.Buffered())) - before
+
+		class := frame.MsgClass()
+		s.metricsMu.Lock()
+		m, ok := s.metrics[class]
+		if !ok {
+			m = &ClassMetrics{}
+			s.metrics[class] = m
+		}
+		m.Bytes += uint64(consumed)
+		s.metricsMu.Unlock()
+
+		s.dispatch(frame.Unwrap())
+	}
+}
+
+// countingReader wraps an io.Reader and records the total number of bytes
+// read so far into n, so readFrames can recover the true stream position
+// (total minus the bufio.Reader's unconsumed Buffered() bytes) for its
+// per-class byte metrics despite bufio reading ahead in large chunks.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
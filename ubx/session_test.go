@@ -0,0 +1,14 @@
+package ubx
+
+import "testing"
+
+func TestFletcher8(t *testing.T) {
+	// UBX-MON-VER poll (class 0x0A, id 0x04, length 0): the worked example
+	// from the u-blox interface description.
+	body := []byte{0x0a, 0x04, 0x00, 0x00}
+
+	ck1, ck2 := fletcher8(body)
+	if ck1 != 0x0e || ck2 != 0x34 {
+		t.Errorf("fletcher8(% x) = (0x%02x, 0x%02x), want (0x0e, 0x34)", body, ck1, ck2)
+	}
+}
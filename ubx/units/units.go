@@ -0,0 +1,28 @@
+// Package units holds the small set of physical unit types generated
+// scale-aware accessors return, so a caller reading, say, lat/lon gets a
+// Degrees back instead of a raw int32 that still needs *1e-7 applied.
+package units
+
+import "fmt"
+
+// Degrees is an angle in decimal degrees, e.g. latitude or longitude.
+type Degrees float64
+
+func (d Degrees) String() string { return fmt.Sprintf("%.7f°", float64(d)) }
+
+// Meters is a distance or height in meters.
+type Meters float64
+
+func (m Meters) String() string { return fmt.Sprintf("%.3fm", float64(m)) }
+
+// MetersPerSecond is a velocity in meters per second.
+type MetersPerSecond float64
+
+func (v MetersPerSecond) String() string { return fmt.Sprintf("%.3fm/s", float64(v)) }
+
+// Seconds is a duration in seconds, kept as a distinct type from
+// time.Duration since generated fields are often fractional counts of
+// seconds (e.g. time-of-week) rather than a monotonic duration.
+type Seconds float64
+
+func (s Seconds) String() string { return fmt.Sprintf("%.9fs", float64(s)) }
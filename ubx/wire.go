@@ -0,0 +1,21 @@
+package ubx
+
+// FrameUBX wraps payload in a complete UBX frame: the two sync bytes,
+// class, id, little-endian length, payload, and the two-byte Fletcher-8
+// checksum. Generated MarshalUBX methods call this once they've built
+// their payload; it's also exported for callers, such as package cfg,
+// that build UBX messages by hand rather than through a generated one.
+func FrameUBX(class, id uint8, payload []byte) []byte {
+	length := len(payload)
+	body := make([]byte, 0, 4+length)
+	body = append(body, class, id, byte(length), byte(length>>8))
+	body = append(body, payload...)
+
+	ck1, ck2 := fletcher8(body)
+
+	frame := make([]byte, 0, 2+len(body)+2)
+	frame = append(frame, syncChar1, syncChar2)
+	frame = append(frame, body...)
+	frame = append(frame, ck1, ck2)
+	return frame
+}
@@ -0,0 +1,130 @@
+package ubxlog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Classic (non-pcapng) link-layer types this package knows how to unwrap.
+// dltUSBPCAP handling is approximate: it strips the fixed-size USBPcap pseudo-header
+// and yields the URB's data bytes directly, without interpreting transfer
+// type or endpoint — good enough for a CDC bulk/interrupt capture of a
+// u-blox module's UBX/NMEA stream, not a general USB dissector.
+const (
+	dltEN10MB     = 1
+	dltUSBPCAP    = 249
+	usbpcapHdrLen = 27
+)
+
+// OpenPCAP reads a classic pcap capture from r and returns a Reader over
+// the UBX/NMEA/RTCM3 bytes carried in its packets (as an Ethernet/IPv4/UDP
+// payload, or as a USBPcap capture of the receiver's serial/CDC traffic),
+// with each decoded Entry timestamped from the pcap packet it was found in.
+func OpenPCAP(r io.Reader, filters ...Filter) (*Reader, error) {
+	var hdr [24]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("ubxlog: pcap global header: %w", err)
+	}
+
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(hdr[:4]) {
+	case 0xa1b2c3d4:
+		order = binary.LittleEndian
+	case 0xd4c3b2a1:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("ubxlog: not a classic pcap capture (bad magic)")
+	}
+	linkType := order.Uint32(hdr[20:24])
+
+	src := &pcapSource{r: r, order: order, linkType: linkType}
+	rd := NewReader(src, filters...)
+	rd.now = func() time.Time { return src.curTime }
+	return rd, nil
+}
+
+// pcapSource presents the payload bytes of successive pcap packets as one
+// continuous io.Reader, tracking the timestamp of whichever packet is
+// currently being read from.
+type pcapSource struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	linkType uint32
+
+	curTime time.Time
+	buf     []byte
+}
+
+func (s *pcapSource) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		ts, payload, err := s.nextPacket()
+		if err != nil {
+			return 0, err
+		}
+		s.curTime = ts
+		s.buf = payload
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+func (s *pcapSource) nextPacket() (time.Time, []byte, error) {
+	var rec [16]byte
+	if _, err := io.ReadFull(s.r, rec[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return time.Time{}, nil, err
+	}
+	sec := s.order.Uint32(rec[0:4])
+	usec := s.order.Uint32(rec[4:8])
+	capLen := s.order.Uint32(rec[8:12])
+	ts := time.Unix(int64(sec), int64(usec)*1000).UTC()
+
+	raw := make([]byte, capLen)
+	if _, err := io.ReadFull(s.r, raw); err != nil {
+		return time.Time{}, nil, fmt.Errorf("ubxlog: truncated pcap packet: %w", err)
+	}
+
+	payload, err := s.unwrap(raw)
+	if err != nil {
+		return s.nextPacket() // not a frame we can decode (ARP, TCP, ...): skip it
+	}
+	return ts, payload, nil
+}
+
+func (s *pcapSource) unwrap(raw []byte) ([]byte, error) {
+	switch s.linkType {
+	case dltEN10MB:
+		return unwrapEthernetUDP(raw)
+	case dltUSBPCAP:
+		if len(raw) < usbpcapHdrLen {
+			return nil, fmt.Errorf("ubxlog: short USBPcap packet")
+		}
+		return raw[usbpcapHdrLen:], nil
+	default:
+		return nil, fmt.Errorf("ubxlog: unsupported pcap link type %d", s.linkType)
+	}
+}
+
+func unwrapEthernetUDP(raw []byte) ([]byte, error) {
+	const ethHdrLen = 14
+	if len(raw) < ethHdrLen+20 {
+		return nil, fmt.Errorf("ubxlog: short Ethernet frame")
+	}
+	if binary.BigEndian.Uint16(raw[12:14]) != 0x0800 {
+		return nil, fmt.Errorf("ubxlog: not IPv4")
+	}
+
+	ip := raw[ethHdrLen:]
+	ihl := int(ip[0]&0x0f) * 4
+	if len(ip) < ihl+8 || ip[9] != 17 {
+		return nil, fmt.Errorf("ubxlog: not UDP")
+	}
+
+	udp := ip[ihl:]
+	return udp[8:], nil
+}
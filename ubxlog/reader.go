@@ -0,0 +1,117 @@
+package ubxlog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// Filter restricts a Reader to messages matching Class and Id. A Filter
+// with Id == AnyID matches every message in Class.
+type Filter struct {
+	Class, Id uint8
+}
+
+// AnyID matches every message id within a Filter's Class.
+const AnyID = 0xff
+
+func (f Filter) matches(msg ubx.Message) bool {
+	if msg.MsgClass() != f.Class {
+		return false
+	}
+	return f.Id == AnyID || msg.MsgId() == f.Id
+}
+
+// Reader decodes Entries from a raw byte stream containing UBX, NMEA and/or
+// RTCM3 frames in any order, such as a u-center .ubx log — u-center
+// interleaves UBX with NMEA on the same stream, and Frame demultiplexing
+// handles that without extra work here.
+type Reader struct {
+	r       *bufio.Reader
+	filters []Filter
+	now     func() time.Time // overridable in tests; defaults to time.Now
+}
+
+// NewReader returns a Reader over r. With no filters, every decodable
+// frame is returned; otherwise only frames matching at least one filter
+// are returned.
+func NewReader(r io.Reader, filters ...Filter) *Reader {
+	return &Reader{r: bufio.NewReader(r), filters: filters, now: time.Now}
+}
+
+// Next decodes and returns the next matching Entry, skipping over frames
+// that fail checksum or have no registered decoder. It returns io.EOF
+// once the underlying stream is exhausted (including when it ends inside
+// a frame), and any other read error as is.
+func (rd *Reader) Next() (Entry, error) {
+	for {
+		frame, err := ubx.ReadFrame(rd.r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Entry{}, io.EOF // a truncated trailing frame also ends the stream
+		}
+		if err != nil {
+			var bad *ubx.BadFrameError
+			if errors.As(err, &bad) {
+				continue // bad checksum or no decoder for this frame: skip it
+			}
+			return Entry{}, err
+		}
+		if rd.accepts(frame) {
+			return Entry{Time: rd.now(), Frame: frame}, nil
+		}
+	}
+}
+
+func (rd *Reader) accepts(msg ubx.Message) bool {
+	if len(rd.filters) == 0 {
+		return true
+	}
+	for _, f := range rd.filters {
+		if f.matches(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Chan starts decoding rd in the background and returns a channel of
+// Entries, closed when rd is exhausted, an error occurs, or ctx is done.
+// Decode errors besides io.EOF are dropped silently, matching Next.
+func (rd *Reader) Chan(ctx context.Context) <-chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		for {
+			e, err := rd.Next()
+			if err != nil {
+				return
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// All drains rd and returns every matching Entry. Intended for logs small
+// enough to hold in memory; for anything larger, call Next in a loop.
+func (rd *Reader) All() ([]Entry, error) {
+	var out []Entry
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, e)
+	}
+}
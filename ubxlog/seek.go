@@ -0,0 +1,107 @@
+package ubxlog
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// indexEntry records where one decoded Entry started in the underlying
+// stream, so Seek can reposition without redecoding everything before it.
+type indexEntry struct {
+	offset int64
+	time   time.Time
+}
+
+// Index supports seeking a raw byte log by time, forward or backward,
+// without rescanning the whole file for every Seek call.
+type Index struct {
+	rs      io.ReadSeeker
+	filters []Filter
+	entries []indexEntry
+}
+
+// BuildIndex scans rs once, end to end, recording the offset and time of
+// every matching Entry. rs is left positioned at EOF; use Index.Seek (not
+// rs directly) to read from it afterwards.
+func BuildIndex(rs io.ReadSeeker, filters ...Filter) (*Index, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	idx := &Index{rs: rs, filters: filters}
+	var total int64
+	cr := &countingReader{r: rs, n: &total}
+	rd := NewReader(cr, filters...)
+
+	for {
+		// rd.r buffers ahead of whatever the frame parser has actually
+		// consumed, so "bytes read from cr" overshoots an entry's real
+		// start by however much sits unconsumed in that buffer — total
+		// minus Buffered() backs that out to the true stream position.
+		before := total - int64(rd.r.Buffered())
+
+		e, err := rd.Next()
+		if err == io.EOF {
+			return idx, nil
+		}
+		if err != nil {
+			return idx, err
+		}
+		idx.entries = append(idx.entries, indexEntry{offset: before, time: e.Time})
+	}
+}
+
+// Seek returns a Reader positioned at the first indexed entry at or after
+// t (forward == true) or the last one at or before t (forward == false).
+// A backward seek to a t before every entry returns the first entry; a
+// forward seek past the last entry returns a Reader positioned at EOF.
+func (idx *Index) Seek(t time.Time, forward bool) (*Reader, error) {
+	i := sort.Search(len(idx.entries), func(i int) bool {
+		return !idx.entries[i].time.Before(t)
+	})
+
+	var offset int64
+	switch {
+	case forward:
+		if i == len(idx.entries) {
+			offset = 0
+			if n, err := idx.rs.Seek(0, io.SeekEnd); err == nil {
+				offset = n
+			}
+		} else {
+			offset = idx.entries[i].offset
+		}
+	default:
+		if len(idx.entries) == 0 {
+			offset = 0
+			break
+		}
+		// i is the first entry with time >= t. An exact match at i is
+		// already "at or before t"; only step back when i overshot t.
+		if i == len(idx.entries) || idx.entries[i].time.After(t) {
+			if i > 0 {
+				i--
+			}
+		}
+		offset = idx.entries[i].offset
+	}
+
+	if _, err := idx.rs.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return NewReader(idx.rs, idx.filters...), nil
+}
+
+// countingReader wraps an io.Reader and records the total number of bytes
+// read so far into n, letting BuildIndex know each Entry's start offset.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,135 @@
+package ubxlog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// testMsg is a minimal registered Message used only to exercise the index
+// and reader machinery; its payload is a single byte identifying which
+// frame it came from.
+type testMsg struct{ n byte }
+
+func (testMsg) MsgClass() uint8 { return 0x01 }
+func (testMsg) MsgId() uint8    { return 0x01 }
+func (m testMsg) N() byte       { return m.n }
+
+func init() {
+	ubx.RegisterDecoder(0x01, 0x01, func(payload []byte) (ubx.Message, error) {
+		return testMsg{n: payload[0]}, nil
+	})
+}
+
+// numbered recovers the concrete testMsg a Frame wraps. Frame embeds
+// Message as an interface field, so only Message's own methods are
+// promoted onto the wrapper — Unwrap is required to get back to the
+// concrete type and its extra N method.
+func numbered(frame ubx.Frame) byte {
+	return frame.Unwrap().(testMsg).N()
+}
+
+func buildLog(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		buf.Write(ubx.FrameUBX(0x01, 0x01, []byte{byte(i)}))
+	}
+	return buf.Bytes()
+}
+
+func TestBuildIndexOffsets(t *testing.T) {
+	data := buildLog(5)
+	rs := bytes.NewReader(data)
+
+	idx, err := BuildIndex(rs)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	if len(idx.entries) != 5 {
+		t.Fatalf("got %d entries, want 5", len(idx.entries))
+	}
+
+	for i, e := range idx.entries {
+		if _, err := rs.Seek(e.offset, io.SeekStart); err != nil {
+			t.Fatalf("entry %d: Seek: %v", i, err)
+		}
+		rd := NewReader(rs)
+		entry, err := rd.Next()
+		if err != nil {
+			t.Fatalf("entry %d: Next: %v", i, err)
+		}
+		if got := numbered(entry.Frame); got != byte(i) {
+			t.Errorf("entry %d: offset %d decodes frame %d, want %d", i, e.offset, got, i)
+		}
+	}
+}
+
+func TestIndexSeek(t *testing.T) {
+	data := buildLog(5)
+	base := time.Unix(1000, 0)
+
+	idx := &Index{rs: bytes.NewReader(data)}
+	for i := 0; i < 5; i++ {
+		idx.entries = append(idx.entries, indexEntry{
+			offset: int64(i) * int64(len(data)/5),
+			time:   base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	read := func(t *testing.T, target time.Time, forward bool) byte {
+		t.Helper()
+		rd, err := idx.Seek(target, forward)
+		if err != nil {
+			t.Fatalf("Seek(%v, %v): %v", target, forward, err)
+		}
+		entry, err := rd.Next()
+		if err != nil {
+			t.Fatalf("Seek(%v, %v): Next: %v", target, forward, err)
+		}
+		return numbered(entry.Frame)
+	}
+
+	// Exact match: forward and backward both land on the matching entry,
+	// not its neighbor.
+	if got := read(t, base.Add(2*time.Minute), true); got != 2 {
+		t.Errorf("forward seek to exact entry 2 = %d, want 2", got)
+	}
+	if got := read(t, base.Add(2*time.Minute), false); got != 2 {
+		t.Errorf("backward seek to exact entry 2 = %d, want 2", got)
+	}
+
+	// Between two entries: forward rounds up, backward rounds down.
+	between := base.Add(2*time.Minute + 30*time.Second)
+	if got := read(t, between, true); got != 3 {
+		t.Errorf("forward seek between entries 2 and 3 = %d, want 3", got)
+	}
+	if got := read(t, between, false); got != 2 {
+		t.Errorf("backward seek between entries 2 and 3 = %d, want 2", got)
+	}
+
+	// Before the first entry: forward clamps to it; backward has nothing
+	// earlier to return but must not panic.
+	before := base.Add(-time.Minute)
+	if got := read(t, before, true); got != 0 {
+		t.Errorf("forward seek before first entry = %d, want 0", got)
+	}
+	if got := read(t, before, false); got != 0 {
+		t.Errorf("backward seek before first entry = %d, want 0", got)
+	}
+
+	// After the last entry: backward clamps to it; forward seeks to EOF.
+	after := base.Add(10 * time.Minute)
+	if got := read(t, after, false); got != 4 {
+		t.Errorf("backward seek after last entry = %d, want 4", got)
+	}
+	rd, err := idx.Seek(after, true)
+	if err != nil {
+		t.Fatalf("forward seek after last entry: %v", err)
+	}
+	if _, err := rd.Next(); err != io.EOF {
+		t.Errorf("forward seek after last entry: Next = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,19 @@
+// Package ubxlog decodes a previously recorded UBX/NMEA/RTCM3 stream —
+// a raw byte capture, a pcap file, or a u-center .ubx log — into
+// timestamped Frames, for fleets that record during a drive and analyze
+// afterwards rather than processing live.
+package ubxlog
+
+import (
+	"time"
+
+	"github.com/Hivemapper/sf-ublox/ubx"
+)
+
+// Entry is one decoded message pulled from a log, with the time it was
+// captured (or time.Now() at decode time for sources, like a raw UBX
+// byte stream, that carry no per-message timestamp of their own).
+type Entry struct {
+	Time  time.Time
+	Frame ubx.Frame
+}